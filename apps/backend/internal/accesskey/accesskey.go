@@ -0,0 +1,253 @@
+// Package accesskey issues and verifies S3-compatible access-key/secret-key
+// pairs scoped to a user, parallel to service.AuthService but for
+// programmatic clients instead of interactive logins.
+package accesskey
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/MinaSamirSaad/go-tasker/internal/server"
+)
+
+const (
+	keyLength    = 8
+	secretLength = 32
+
+	// resolveCacheTTL bounds how stale a cached key can be on the SigV4
+	// validation hot path. SetStatus deletes the cached entry, so a status
+	// change is seen by the next lookup; the TTL bounds a stale copy
+	// re-cached by a Resolve that read the row before the change.
+	resolveCacheTTL = 60 * time.Second
+
+	StatusActive   = "active"
+	StatusDisabled = "disabled"
+)
+
+var alphanumeric = []byte("ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789")
+
+var (
+	ErrNotFound            = errors.New("access key not found")
+	ErrInvalidBucketPrefix = errors.New("bucket_prefix must be rooted at the caller's own user prefix")
+)
+
+// AccessKey is a single S3-compatible credential scoped to a user.
+type AccessKey struct {
+	AccessKeyID   string    `json:"access_key_id"`
+	SecretKey     string    `json:"-"`
+	UserID        string    `json:"user_id"`
+	Status        string    `json:"status"`
+	BucketPrefix  string    `json:"bucket_prefix"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// Generated is returned only once, at creation time, since it's the only
+// moment the secret key is available in plaintext to the caller.
+type Generated struct {
+	AccessKey
+	SecretKey string `json:"secret_key"`
+}
+
+// Service generates and validates access keys, persisting them in
+// Postgres and caching lookups in Redis for the SigV4 validation hot path.
+type Service struct {
+	db    *sql.DB
+	redis *redis.Client
+}
+
+func NewService(s *server.Server) *Service {
+	return &Service{
+		db:    s.DB,
+		redis: s.Redis,
+	}
+}
+
+// Generate creates and persists a new access key for userID, scoped to
+// bucketPrefix beneath the user's own namespace (UserPrefix(userID)). An
+// empty bucketPrefix defaults to that whole namespace; a caller-supplied
+// bucketPrefix that isn't rooted there is rejected with
+// ErrInvalidBucketPrefix, since nothing else stops a user asking for a
+// wider prefix over the shared bucket. The secret key is only ever
+// available on the returned value; callers must display it to the user
+// immediately and cannot retrieve it again.
+func (s *Service) Generate(ctx context.Context, userID, bucketPrefix string) (*Generated, error) {
+	bucketPrefix, err := scopeBucketPrefix(userID, bucketPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	accessKeyID, err := randomString(keyLength)
+	if err != nil {
+		return nil, fmt.Errorf("generate access key id: %w", err)
+	}
+	secretKey, err := randomString(secretLength)
+	if err != nil {
+		return nil, fmt.Errorf("generate secret key: %w", err)
+	}
+
+	now := time.Now().UTC()
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO access_keys (access_key_id, secret_key, user_id, status, bucket_prefix, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $6)
+	`, accessKeyID, secretKey, userID, StatusActive, bucketPrefix, now)
+	if err != nil {
+		return nil, fmt.Errorf("insert access key: %w", err)
+	}
+
+	return &Generated{
+		AccessKey: AccessKey{
+			AccessKeyID:  accessKeyID,
+			UserID:       userID,
+			Status:       StatusActive,
+			BucketPrefix: bucketPrefix,
+			CreatedAt:    now,
+			UpdatedAt:    now,
+		},
+		SecretKey: secretKey,
+	}, nil
+}
+
+// List returns every access key belonging to userID. Secret keys are
+// never included; they're only returned once, from Generate.
+func (s *Service) List(ctx context.Context, userID string) ([]AccessKey, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT access_key_id, user_id, status, bucket_prefix, created_at, updated_at
+		FROM access_keys
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []AccessKey
+	for rows.Next() {
+		var k AccessKey
+		if err := rows.Scan(&k.AccessKeyID, &k.UserID, &k.Status, &k.BucketPrefix, &k.CreatedAt, &k.UpdatedAt); err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	return keys, rows.Err()
+}
+
+// SetStatus enables or disables an access key owned by userID and
+// invalidates its cache entry so the change takes effect immediately.
+// It returns ErrNotFound both when the key doesn't exist and when it
+// belongs to a different user, so callers can't use the response to probe
+// for valid access key IDs they don't own.
+func (s *Service) SetStatus(ctx context.Context, accessKeyID, userID, status string) error {
+	res, err := s.db.ExecContext(ctx, `
+		UPDATE access_keys SET status = $3, updated_at = now() WHERE access_key_id = $1 AND user_id = $2
+	`, accessKeyID, userID, status)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+
+	_ = s.redis.Del(ctx, resolveCacheKey(accessKeyID)).Err()
+	return nil
+}
+
+// Resolve looks up an access key by ID, consulting the Redis cache first.
+// Used on the SigV4 validation hot path.
+func (s *Service) Resolve(ctx context.Context, accessKeyID string) (*AccessKey, string, error) {
+	if cached, secret, err := s.resolveFromCache(ctx, accessKeyID); err == nil {
+		return cached, secret, nil
+	}
+
+	var k AccessKey
+	var secretKey string
+	err := s.db.QueryRowContext(ctx, `
+		SELECT access_key_id, secret_key, user_id, status, bucket_prefix, created_at, updated_at
+		FROM access_keys
+		WHERE access_key_id = $1
+	`, accessKeyID).Scan(&k.AccessKeyID, &secretKey, &k.UserID, &k.Status, &k.BucketPrefix, &k.CreatedAt, &k.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, "", ErrNotFound
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	s.cache(ctx, &k, secretKey)
+	return &k, secretKey, nil
+}
+
+type cachedKey struct {
+	AccessKey
+	SecretKey string `json:"secret_key"`
+}
+
+func (s *Service) resolveFromCache(ctx context.Context, accessKeyID string) (*AccessKey, string, error) {
+	raw, err := s.redis.Get(ctx, resolveCacheKey(accessKeyID)).Bytes()
+	if err != nil {
+		return nil, "", err
+	}
+
+	var c cachedKey
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, "", err
+	}
+	return &c.AccessKey, c.SecretKey, nil
+}
+
+func (s *Service) cache(ctx context.Context, k *AccessKey, secretKey string) {
+	raw, err := json.Marshal(cachedKey{AccessKey: *k, SecretKey: secretKey})
+	if err != nil {
+		return
+	}
+	_ = s.redis.Set(ctx, resolveCacheKey(k.AccessKeyID), raw, resolveCacheTTL).Err()
+}
+
+func resolveCacheKey(accessKeyID string) string {
+	return "accesskey:resolve:" + accessKeyID
+}
+
+// UserPrefix returns the object-key prefix a user's own data is
+// namespaced under. Every AccessKey's BucketPrefix is confined beneath
+// this, and upload/presign services must write objects under it, so a
+// bucket-prefix ACL actually has something meaningful to scope against.
+func UserPrefix(userID string) string {
+	return "users/" + userID + "/"
+}
+
+// scopeBucketPrefix confines a caller-supplied prefix to userID's own
+// namespace. An empty prefix defaults to the full namespace rather than
+// "unrestricted" — the ACL can only narrow access within it, never widen
+// it past what the requesting user already owns.
+func scopeBucketPrefix(userID, requested string) (string, error) {
+	root := UserPrefix(userID)
+	if requested == "" {
+		return root, nil
+	}
+	if !strings.HasPrefix(requested, root) {
+		return "", ErrInvalidBucketPrefix
+	}
+	return requested, nil
+}
+
+func randomString(n int) (string, error) {
+	out := make([]byte, n)
+	for i := range out {
+		idx, err := rand.Int(rand.Reader, big.NewInt(int64(len(alphanumeric))))
+		if err != nil {
+			return "", err
+		}
+		out[i] = alphanumeric[idx.Int64()]
+	}
+	return string(out), nil
+}
@@ -53,14 +53,45 @@ type AWSConfig struct {
 	AccessKeyID     string `koanf:"access_key_id" validate:"required"`
 	SecretAccessKey string `koanf:"secret_access_key" validate:"required"`
 	UploadBucket    string `koanf:"upload_bucket" validate:"required"`
-	EndpointURL     string `koanf:"endpoint_url"` // optional for AWS
+	EndpointURL     string `koanf:"endpoint_url"` // optional for AWS, required for MinIO/other S3-compatible endpoints
+	// StorageURL selects the storage.Backend driver, e.g. "s3://my-bucket",
+	// "file:///var/data/uploads" or "memory://" in tests. If empty it
+	// defaults to "s3://" + UploadBucket so existing config keeps working.
+	StorageURL string `koanf:"storage_url"`
+	// PresignTTLSeconds bounds how long presigned upload/download URLs
+	// stay valid; requests for a longer TTL are clamped to MaxPresignTTLSeconds.
+	PresignTTLSeconds int `koanf:"presign_ttl_seconds"`
 }
 
+// MaxPresignTTLSeconds is the hard ceiling on presigned URL lifetime,
+// regardless of what a caller requests.
+const MaxPresignTTLSeconds = 3600
+
 type CronConfig struct {
-	ArchiveDaysThreshold        int `koanf:"archive_days_threshold"`
-	BatchSize                   int `koanf:"batch_size"`
-	ReminderHours               int `koanf:"reminder_hours"`
-	MaxTodosPerUserNotification int `koanf:"max_todos_per_user_notification"`
+	ArchiveDaysThreshold        int          `koanf:"archive_days_threshold"`
+	BatchSize                   int          `koanf:"batch_size"`
+	ReminderHours               int          `koanf:"reminder_hours"`
+	MaxTodosPerUserNotification int          `koanf:"max_todos_per_user_notification"`
+	Backup                      BackupConfig `koanf:"backup"`
+}
+
+// BackupConfig controls the scheduled database backup job that dumps
+// Postgres and uploads the archive to AWSConfig.UploadBucket.
+type BackupConfig struct {
+	Enabled bool `koanf:"enabled"`
+	// CronExpr is a standard 5-field cron expression controlling when the
+	// backup job runs.
+	CronExpr string `koanf:"cron_expr"`
+	// KeyPrefixTemplate supports the placeholders {timestamp} and {date},
+	// e.g. "backups/{date}/tasker-{timestamp}.sql.gz".
+	KeyPrefixTemplate string `koanf:"key_prefix_template"`
+	Compress          bool   `koanf:"compress"`
+	// RetentionCount keeps at most the N most recent backups; 0 disables
+	// the count-based policy.
+	RetentionCount int `koanf:"retention_count"`
+	// RetentionDays deletes backups older than this many days; 0 disables
+	// the age-based policy. Both policies may be combined.
+	RetentionDays int `koanf:"retention_days"`
 }
 
 func DefaultCronConfig() *CronConfig {
@@ -69,6 +100,14 @@ func DefaultCronConfig() *CronConfig {
 		BatchSize:                   100,
 		ReminderHours:               24,
 		MaxTodosPerUserNotification: 10,
+		Backup: BackupConfig{
+			Enabled:           false,
+			CronExpr:          "0 3 * * *",
+			KeyPrefixTemplate: "backups/{date}/tasker-{timestamp}.sql.gz",
+			Compress:          true,
+			RetentionCount:    7,
+			RetentionDays:     30,
+		},
 	}
 }
 
@@ -148,6 +187,12 @@ func LoadConfig() (*Config, error) {
 		mainConfig.Cron = DefaultCronConfig()
 	}
 
+	if mainConfig.AWS.PresignTTLSeconds <= 0 {
+		mainConfig.AWS.PresignTTLSeconds = 900
+	} else if mainConfig.AWS.PresignTTLSeconds > MaxPresignTTLSeconds {
+		mainConfig.AWS.PresignTTLSeconds = MaxPresignTTLSeconds
+	}
+
 	return mainConfig, nil
 }
 
@@ -0,0 +1,230 @@
+// Package errordetail classifies and aggregates failures from background
+// jobs and HTTP handlers into Postgres-backed groups, so operators get
+// actionable aggregates ("this route has failed 40 times with a permanent
+// error, most recently a minute ago") instead of raw log spelunking. A
+// group's count is cumulative since its first occurrence; it is not
+// limited to a time window.
+package errordetail
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"time"
+
+	"github.com/MinaSamirSaad/go-tasker/internal/server"
+)
+
+const (
+	ClassTransient = "transient"
+	ClassPermanent = "permanent"
+
+	// maxSamplesPerGroup is how many of a group's most recent samples
+	// Record keeps; older ones are deleted.
+	maxSamplesPerGroup = 20
+)
+
+var ErrGroupNotFound = errors.New("error group not found")
+
+// Rule classifies an error for a subsystem by matching its message against
+// Pattern. Rules are checked in order; the first match wins. Subsystem
+// may be "*" to apply to every subsystem.
+type Rule struct {
+	Subsystem      string
+	Pattern        *regexp.Regexp
+	Classification string
+	Retryable      bool
+}
+
+// DefaultRules covers the common transient-vs-permanent failure shapes
+// seen across jobs and HTTP handlers. Callers can prepend more specific
+// rules via Service.AddRule; the first match wins.
+func DefaultRules() []Rule {
+	return []Rule{
+		{Subsystem: "*", Pattern: regexp.MustCompile(`(?i)context deadline exceeded|i/o timeout|connection reset|connection refused|broken pipe`), Classification: ClassTransient, Retryable: true},
+		{Subsystem: "*", Pattern: regexp.MustCompile(`(?i)too many connections|deadlock detected`), Classification: ClassTransient, Retryable: true},
+		{Subsystem: "*", Pattern: regexp.MustCompile(`(?i)duplicate key value|violates foreign key constraint|violates check constraint`), Classification: ClassPermanent, Retryable: false},
+		{Subsystem: "*", Pattern: regexp.MustCompile(`(?i)invalid input syntax|validation failed|unauthorized|forbidden|not found`), Classification: ClassPermanent, Retryable: false},
+	}
+}
+
+// Group is one aggregate of failures sharing a subsystem, error code and
+// error type. Count covers every occurrence since FirstSeen.
+type Group struct {
+	ID             string
+	Subsystem      string
+	Code           string
+	ErrorType      string
+	Classification string
+	Retryable      bool
+	FirstSeen      time.Time
+	LastSeen       time.Time
+	Count          int64
+	SampleStack    string
+	SampleRequestID string
+}
+
+// Sample is one occurrence recorded against a Group, kept so operators can
+// inspect recent request IDs/stacks instead of only the aggregate.
+type Sample struct {
+	RequestID  string
+	Stack      string
+	OccurredAt time.Time
+}
+
+// Filter narrows List to a subset of groups.
+type Filter struct {
+	Subsystem string
+	Code      string
+	Since     time.Time
+}
+
+// Service persists classified error groups and their recent samples in
+// Postgres.
+type Service struct {
+	db    *sql.DB
+	rules []Rule
+}
+
+func NewService(s *server.Server) *Service {
+	return &Service{
+		db:    s.DB,
+		rules: DefaultRules(),
+	}
+}
+
+// AddRule prepends a subsystem-specific classification rule, taking
+// priority over DefaultRules.
+func (s *Service) AddRule(rule Rule) {
+	s.rules = append([]Rule{rule}, s.rules...)
+}
+
+func (s *Service) classify(subsystem, message string) (classification string, retryable bool) {
+	for _, rule := range s.rules {
+		if rule.Subsystem != "*" && rule.Subsystem != subsystem {
+			continue
+		}
+		if rule.Pattern.MatchString(message) {
+			return rule.Classification, rule.Retryable
+		}
+	}
+	// Unrecognized errors default to permanent/non-retryable so an
+	// unclassified bug doesn't get silently retried forever.
+	return ClassPermanent, false
+}
+
+// Record classifies err for subsystem and upserts it into the matching
+// group, recording a bounded number of recent samples. It returns
+// the resulting classification so callers (e.g. the job runner) can
+// decide whether to retry.
+func (s *Service) Record(ctx context.Context, subsystem, code, errType string, err error, requestID, stack string) (classification string, retryable bool, recordErr error) {
+	message := err.Error()
+	classification, retryable = s.classify(subsystem, message)
+
+	var groupID string
+	recordErr = s.db.QueryRowContext(ctx, `
+		INSERT INTO error_groups (subsystem, code, error_type, classification, retryable, first_seen, last_seen, count, sample_stack, sample_request_id)
+		VALUES ($1, $2, $3, $4, $5, now(), now(), 1, $6, $7)
+		ON CONFLICT (subsystem, code, error_type) DO UPDATE SET
+			last_seen = now(),
+			count = error_groups.count + 1,
+			classification = EXCLUDED.classification,
+			retryable = EXCLUDED.retryable,
+			sample_stack = EXCLUDED.sample_stack,
+			sample_request_id = EXCLUDED.sample_request_id
+		RETURNING id
+	`, subsystem, code, errType, classification, retryable, stack, requestID).Scan(&groupID)
+	if recordErr != nil {
+		return classification, retryable, recordErr
+	}
+
+	_, recordErr = s.db.ExecContext(ctx, `
+		INSERT INTO error_samples (group_id, request_id, stack, occurred_at)
+		VALUES ($1, $2, $3, now())
+	`, groupID, requestID, stack)
+	if recordErr != nil {
+		return classification, retryable, recordErr
+	}
+
+	_, recordErr = s.db.ExecContext(ctx, `
+		DELETE FROM error_samples
+		WHERE group_id = $1 AND id NOT IN (
+			SELECT id FROM error_samples WHERE group_id = $1 ORDER BY occurred_at DESC, id DESC LIMIT $2
+		)
+	`, groupID, maxSamplesPerGroup)
+
+	return classification, retryable, recordErr
+}
+
+// List returns error groups matching filter, most recently seen first.
+func (s *Service) List(ctx context.Context, filter Filter) ([]Group, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, subsystem, code, error_type, classification, retryable, first_seen, last_seen, count, sample_stack, sample_request_id
+		FROM error_groups
+		WHERE ($1 = '' OR subsystem = $1)
+		  AND ($2 = '' OR code = $2)
+		  AND ($3::timestamptz IS NULL OR last_seen >= $3)
+		ORDER BY last_seen DESC
+	`, filter.Subsystem, filter.Code, nullableTime(filter.Since))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var groups []Group
+	for rows.Next() {
+		var g Group
+		if err := rows.Scan(&g.ID, &g.Subsystem, &g.Code, &g.ErrorType, &g.Classification, &g.Retryable, &g.FirstSeen, &g.LastSeen, &g.Count, &g.SampleStack, &g.SampleRequestID); err != nil {
+			return nil, err
+		}
+		groups = append(groups, g)
+	}
+	return groups, rows.Err()
+}
+
+// Get returns a single group along with its most recent samples.
+func (s *Service) Get(ctx context.Context, id string) (*Group, []Sample, error) {
+	var g Group
+	err := s.db.QueryRowContext(ctx, `
+		SELECT id, subsystem, code, error_type, classification, retryable, first_seen, last_seen, count, sample_stack, sample_request_id
+		FROM error_groups
+		WHERE id = $1
+	`, id).Scan(&g.ID, &g.Subsystem, &g.Code, &g.ErrorType, &g.Classification, &g.Retryable, &g.FirstSeen, &g.LastSeen, &g.Count, &g.SampleStack, &g.SampleRequestID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, ErrGroupNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT request_id, stack, occurred_at
+		FROM error_samples
+		WHERE group_id = $1
+		ORDER BY occurred_at DESC
+		LIMIT 20
+	`, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var samples []Sample
+	for rows.Next() {
+		var sample Sample
+		if err := rows.Scan(&sample.RequestID, &sample.Stack, &sample.OccurredAt); err != nil {
+			return nil, nil, err
+		}
+		samples = append(samples, sample)
+	}
+
+	return &g, samples, rows.Err()
+}
+
+func nullableTime(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return t
+}
@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/MinaSamirSaad/go-boilerplate/internal/server"
+	"github.com/MinaSamirSaad/go-tasker/internal/accesskey"
+)
+
+// AccessKeyHandler lets an authenticated user manage their own S3-compatible
+// access keys.
+type AccessKeyHandler struct {
+	server *server.Server
+	keys   *accesskey.Service
+}
+
+func NewAccessKeyHandler(s *server.Server, keys *accesskey.Service) *AccessKeyHandler {
+	return &AccessKeyHandler{server: s, keys: keys}
+}
+
+// BucketPrefix optionally narrows the key's ACL to a sub-prefix of the
+// caller's own namespace (accesskey.UserPrefix); it can never widen
+// access beyond it, regardless of what the caller sends here.
+type createAccessKeyRequest struct {
+	BucketPrefix string `json:"bucket_prefix"`
+}
+
+// Create generates a new access key for the authenticated user, scoped
+// to their own namespace. The secret key is only ever present in this
+// response.
+// POST /keys
+func (h *AccessKeyHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req createAccessKeyRequest
+	if r.ContentLength > 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	userID, err := ownerFromRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	generated, err := h.keys.Generate(r.Context(), userID, req.BucketPrefix)
+	if err != nil {
+		writeAccessKeyError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(generated)
+}
+
+// List returns the authenticated user's access keys. Secret keys are
+// never included.
+// GET /keys
+func (h *AccessKeyHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, err := ownerFromRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	keys, err := h.keys.List(r.Context(), userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(keys)
+}
+
+// Delete disables an access key. The key is kept, not removed, and
+// PATCH /keys/{key} can set it back to active.
+// DELETE /keys/{key}
+func (h *AccessKeyHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	key := pathParam(r)
+	userID, err := ownerFromRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.keys.SetStatus(r.Context(), key, userID, accesskey.StatusDisabled); err != nil {
+		writeAccessKeyError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type patchAccessKeyRequest struct {
+	Status string `json:"status"`
+}
+
+// Patch enables or disables an access key.
+// PATCH /keys/{key}
+func (h *AccessKeyHandler) Patch(w http.ResponseWriter, r *http.Request) {
+	key := pathParam(r)
+	userID, err := ownerFromRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	var req patchAccessKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.Status != accesskey.StatusActive && req.Status != accesskey.StatusDisabled {
+		http.Error(w, "status must be \"active\" or \"disabled\"", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.keys.SetStatus(r.Context(), key, userID, req.Status); err != nil {
+		writeAccessKeyError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeAccessKeyError(w http.ResponseWriter, err error) {
+	switch err {
+	case accesskey.ErrNotFound:
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case accesskey.ErrInvalidBucketPrefix:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/MinaSamirSaad/go-boilerplate/internal/server"
+	"github.com/MinaSamirSaad/go-tasker/internal/service"
+)
+
+// BackupHandler exposes admin endpoints for triggering and inspecting
+// the scheduled database backup job.
+type BackupHandler struct {
+	server     *server.Server
+	autoBackup *service.AutoBackupService
+}
+
+func NewBackupHandler(s *server.Server, autoBackup *service.AutoBackupService) *BackupHandler {
+	return &BackupHandler{
+		server:     s,
+		autoBackup: autoBackup,
+	}
+}
+
+// RunNow triggers an on-demand backup and responds once it completes.
+// POST /admin/backups/run
+func (h *BackupHandler) RunNow(w http.ResponseWriter, r *http.Request) {
+	key, err := h.autoBackup.Run(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]string{"key": key})
+}
+
+// List returns the backup objects currently stored under the configured
+// key prefix.
+// GET /admin/backups
+func (h *BackupHandler) List(w http.ResponseWriter, r *http.Request) {
+	objects, err := h.autoBackup.List(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(objects)
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/MinaSamirSaad/go-boilerplate/internal/server"
+	"github.com/MinaSamirSaad/go-tasker/internal/errordetail"
+)
+
+// ErrorReportHandler exposes aggregated, classified failure data to
+// operators so they don't have to spelunk raw logs to see what's broken.
+type ErrorReportHandler struct {
+	server *server.Server
+	errors *errordetail.Service
+}
+
+func NewErrorReportHandler(s *server.Server, errors *errordetail.Service) *ErrorReportHandler {
+	return &ErrorReportHandler{server: s, errors: errors}
+}
+
+// List returns error groups, optionally filtered by subsystem, code and/or
+// a "since" RFC3339 timestamp.
+// GET /admin/errors
+func (h *ErrorReportHandler) List(w http.ResponseWriter, r *http.Request) {
+	filter := errordetail.Filter{
+		Subsystem: r.URL.Query().Get("subsystem"),
+		Code:      r.URL.Query().Get("code"),
+	}
+	if since := r.URL.Query().Get("since"); since != "" {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			http.Error(w, "since must be an RFC3339 timestamp", http.StatusBadRequest)
+			return
+		}
+		filter.Since = t
+	}
+
+	groups, err := h.errors.List(r.Context(), filter)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(groups)
+}
+
+// Get returns a single error group and its most recent samples.
+// GET /admin/errors/{id}
+func (h *ErrorReportHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id := pathParam(r)
+
+	group, samples, err := h.errors.Get(r.Context(), id)
+	if err != nil {
+		if err == errordetail.ErrGroupNotFound {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"group":   group,
+		"samples": samples,
+	})
+}
@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/MinaSamirSaad/go-tasker/internal/errordetail"
+)
+
+// ErrorReportingMiddleware classifies every 5xx response into errordetail,
+// grouped by route + status code, so operators see it aggregated in
+// GET /admin/errors instead of having to grep logs.
+func ErrorReportingMiddleware(reporter *errordetail.Service) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(rec, r)
+
+			if rec.status < 500 {
+				return
+			}
+
+			requestID := r.Header.Get("X-Request-Id")
+			route := r.Method + " " + r.URL.Path
+			err := fmt.Errorf("%s returned %d", route, rec.status)
+
+			// Record is best-effort: a failure to classify a failure
+			// shouldn't surface to the client or mask the original error.
+			_, _, _ = reporter.Record(r.Context(), "http", fmt.Sprintf("%d", rec.status), route, err, requestID, "")
+		})
+	}
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
@@ -3,16 +3,33 @@ package handler
 import (
 	"github.com/MinaSamirSaad/go-boilerplate/internal/server"
 	"github.com/MinaSamirSaad/go-boilerplate/internal/service"
+	"github.com/MinaSamirSaad/go-tasker/internal/accesskey"
+	"github.com/MinaSamirSaad/go-tasker/internal/errordetail"
 )
 
 type Handlers struct {
-	Health  *HealthHandler
-	OpenAPI *OpenAPIHandler
+	Health      *HealthHandler
+	OpenAPI     *OpenAPIHandler
+	Backup      *BackupHandler
+	Upload      *UploadHandler
+	AccessKeys  *AccessKeyHandler
+	S3Proxy     *S3ProxyHandler
+	Presign     *PresignHandler
+	ErrorReport *ErrorReportHandler
 }
 
 func NewHandlers(s *server.Server, services *service.Services) *Handlers {
+	accessKeys := accesskey.NewService(s)
+	errorDetail := errordetail.NewService(s)
+
 	return &Handlers{
-		Health:  NewHealthHandler(s),
-		OpenAPI: NewOpenAPIHandler(s),
+		Health:      NewHealthHandler(s),
+		OpenAPI:     NewOpenAPIHandler(s),
+		Backup:      NewBackupHandler(s, services.AutoBackup),
+		Upload:      NewUploadHandler(s, services.Upload),
+		AccessKeys:  NewAccessKeyHandler(s, accessKeys),
+		S3Proxy:     NewS3ProxyHandler(s, services.Storage),
+		Presign:     NewPresignHandler(s, services.Presign),
+		ErrorReport: NewErrorReportHandler(s, errorDetail),
 	}
 }
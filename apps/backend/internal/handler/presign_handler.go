@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/MinaSamirSaad/go-boilerplate/internal/server"
+	"github.com/MinaSamirSaad/go-tasker/internal/service"
+)
+
+// PresignHandler lets clients upload/download files directly to/from
+// storage via presigned URLs, without streaming the bytes through the API
+// server.
+type PresignHandler struct {
+	server  *server.Server
+	presign *service.PresignService
+}
+
+func NewPresignHandler(s *server.Server, presign *service.PresignService) *PresignHandler {
+	return &PresignHandler{server: s, presign: presign}
+}
+
+type presignUploadRequest struct {
+	ContentType  string `json:"content_type"`
+	ExpectedSize int64  `json:"expected_size"`
+}
+
+type presignUploadResponse struct {
+	ID        string            `json:"id"`
+	UploadURL string            `json:"upload_url"`
+	Headers   map[string]string `json:"headers"`
+}
+
+// CreateUpload issues a presigned PUT URL for a new file.
+// POST /files/presign-upload
+func (h *PresignHandler) CreateUpload(w http.ResponseWriter, r *http.Request) {
+	var req presignUploadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.ExpectedSize <= 0 {
+		http.Error(w, "expected_size must be greater than zero", http.StatusBadRequest)
+		return
+	}
+
+	owner, err := ownerFromRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	pending, uploadURL, err := h.presign.CreateUpload(r.Context(), owner, req.ContentType, req.ExpectedSize)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	resp := presignUploadResponse{
+		ID:        pending.ID,
+		UploadURL: uploadURL,
+		Headers:   map[string]string{"Content-Type": req.ContentType},
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// PresignDownload issues a presigned GET URL for a previously completed
+// upload.
+// GET /files/{id}/presign-download
+func (h *PresignHandler) PresignDownload(w http.ResponseWriter, r *http.Request) {
+	id := secondToLastPathParam(r)
+
+	owner, err := ownerFromRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	url, err := h.presign.PresignDownload(r.Context(), id, owner)
+	if err != nil {
+		writePresignError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]string{"download_url": url})
+}
+
+// Complete verifies an upload completed successfully and commits it.
+// POST /files/{id}/complete
+func (h *PresignHandler) Complete(w http.ResponseWriter, r *http.Request) {
+	id := secondToLastPathParam(r)
+
+	owner, err := ownerFromRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	pending, err := h.presign.Complete(r.Context(), id, owner)
+	if err != nil {
+		writePresignError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]bool{"committed": pending.Committed})
+}
+
+func writePresignError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrPendingUploadNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, service.ErrPendingUploadExpired):
+		http.Error(w, err.Error(), http.StatusGone)
+	case errors.Is(err, service.ErrUploadSizeMismatch):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/MinaSamirSaad/go-tasker/internal/storage"
+)
+
+func TestParseAuthorizationHeader(t *testing.T) {
+	header := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20260725/us-east-1/s3/aws4_request, SignedHeaders=host;x-amz-date, Signature=deadbeef"
+
+	accessKeyID, signature, signedHeaders, err := parseAuthorizationHeader(header)
+	if err != nil {
+		t.Fatalf("parseAuthorizationHeader: %v", err)
+	}
+	if accessKeyID != "AKIDEXAMPLE" {
+		t.Fatalf("accessKeyID = %q, want AKIDEXAMPLE", accessKeyID)
+	}
+	if signature != "deadbeef" {
+		t.Fatalf("signature = %q, want deadbeef", signature)
+	}
+	if len(signedHeaders) != 2 || signedHeaders[0] != "host" || signedHeaders[1] != "x-amz-date" {
+		t.Fatalf("signedHeaders = %v, want [host x-amz-date]", signedHeaders)
+	}
+}
+
+func TestParseAuthorizationHeaderRejectsMalformed(t *testing.T) {
+	if _, _, _, err := parseAuthorizationHeader("Bearer sometoken"); err != errMalformedAuthHeader {
+		t.Fatalf("expected errMalformedAuthHeader, got %v", err)
+	}
+	if _, _, _, err := parseAuthorizationHeader("AWS4-HMAC-SHA256 Credential=/20260725/us-east-1/s3/aws4_request, Signature=deadbeef"); err != errMalformedAuthHeader {
+		t.Fatalf("expected errMalformedAuthHeader for empty access key id, got %v", err)
+	}
+}
+
+func TestSignRequestChangesWithPayloadHash(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPut, "/s3/users/alice/files/report.txt", strings.NewReader("hello world"))
+	req.Header.Set("Host", "example.com")
+	req.Header.Set("X-Amz-Date", "20260725T000000Z")
+	signedHeaders := []string{"host", "x-amz-date"}
+
+	sig := signRequest(req, "supersecret", signedHeaders, hashHex("hello world"))
+	tamperedSig := signRequest(req, "supersecret", signedHeaders, hashHex("goodbye world"))
+	if sig == tamperedSig {
+		t.Fatal("signature should change when the signed payload hash changes")
+	}
+
+	wrongSecretSig := signRequest(req, "wrongsecret", signedHeaders, hashHex("hello world"))
+	if sig == wrongSecretSig {
+		t.Fatal("signature should change when the secret key changes")
+	}
+}
+
+func TestObjectProxyRoundTripsThroughMemoryBackend(t *testing.T) {
+	h := &S3ProxyHandler{storage: storage.NewMemoryBackend()}
+
+	putReq := httptest.NewRequest(http.MethodPut, "/s3/users/alice/files/report.txt", bytes.NewBufferString("hello"))
+	putReq = putReq.WithContext(context.WithValue(putReq.Context(), s3ProxyBucketPrefixContextKey, "users/alice/"))
+	putRec := httptest.NewRecorder()
+	h.Object(putRec, putReq)
+	if putRec.Code != http.StatusOK {
+		t.Fatalf("PUT status = %d, want 200", putRec.Code)
+	}
+
+	getReq := httptest.NewRequest(http.MethodGet, "/s3/users/alice/files/report.txt", nil)
+	getReq = getReq.WithContext(context.WithValue(getReq.Context(), s3ProxyBucketPrefixContextKey, "users/alice/"))
+	getRec := httptest.NewRecorder()
+	h.Object(getRec, getReq)
+	if getRec.Code != http.StatusOK {
+		t.Fatalf("GET status = %d, want 200", getRec.Code)
+	}
+	if getRec.Body.String() != "hello" {
+		t.Fatalf("GET body = %q, want %q", getRec.Body.String(), "hello")
+	}
+
+	delReq := httptest.NewRequest(http.MethodDelete, "/s3/users/alice/files/report.txt", nil)
+	delReq = delReq.WithContext(context.WithValue(delReq.Context(), s3ProxyBucketPrefixContextKey, "users/alice/"))
+	delRec := httptest.NewRecorder()
+	h.Object(delRec, delReq)
+	if delRec.Code != http.StatusNoContent {
+		t.Fatalf("DELETE status = %d, want 204", delRec.Code)
+	}
+}
+
+func TestObjectProxyRejectsOutOfScopePrefix(t *testing.T) {
+	h := &S3ProxyHandler{storage: storage.NewMemoryBackend()}
+
+	req := httptest.NewRequest(http.MethodPut, "/s3/users/bob/files/report.txt", bytes.NewBufferString("hello"))
+	req = req.WithContext(context.WithValue(req.Context(), s3ProxyBucketPrefixContextKey, "users/alice/"))
+	rec := httptest.NewRecorder()
+	h.Object(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("status = %d, want 403", rec.Code)
+	}
+}
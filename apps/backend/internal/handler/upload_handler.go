@@ -0,0 +1,216 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/MinaSamirSaad/go-boilerplate/internal/server"
+	"github.com/MinaSamirSaad/go-tasker/internal/service"
+)
+
+// authUserIDContextKey is the request-context key auth middleware stores
+// the authenticated user ID under.
+type contextKey string
+
+const authUserIDContextKey contextKey = "user_id"
+
+// UploadHandler implements resumable chunked uploads over HTTP, mirroring
+// the Docker Registry blob-upload protocol: POST creates a session, PATCH
+// appends a chunk, PUT finalizes, GET reports progress, DELETE aborts.
+type UploadHandler struct {
+	server  *server.Server
+	uploads *service.UploadService
+}
+
+func NewUploadHandler(s *server.Server, uploads *service.UploadService) *UploadHandler {
+	return &UploadHandler{server: s, uploads: uploads}
+}
+
+type createUploadRequest struct {
+	ExpectedSize int64 `json:"expected_size"`
+}
+
+// Create starts a new upload session.
+// POST /uploads
+func (h *UploadHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req createUploadRequest
+	if r.ContentLength > 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	owner, err := ownerFromRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	session, err := h.uploads.CreateSession(r.Context(), owner, req.ExpectedSize)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Location", "/uploads/"+session.ID)
+	w.Header().Set("Range", "0-0")
+	w.Header().Set("Docker-Upload-UUID", session.ID)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// Append appends a chunk of bytes to an in-progress upload.
+// PATCH /uploads/{uuid}
+func (h *UploadHandler) Append(w http.ResponseWriter, r *http.Request) {
+	id := pathParam(r)
+
+	owner, err := ownerFromRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	rangeStart, err := parseContentRangeStart(r.Header.Get("Content-Range"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "failed to read request body", http.StatusBadRequest)
+		return
+	}
+
+	session, err := h.uploads.AppendChunk(r.Context(), id, owner, rangeStart, body)
+	if err != nil {
+		writeUploadError(w, err)
+		return
+	}
+
+	w.Header().Set("Range", fmt.Sprintf("0-%d", session.Offset))
+	w.Header().Set("Docker-Upload-UUID", session.ID)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// Finalize completes the upload once the client has sent every chunk and
+// knows the expected digest.
+// PUT /uploads/{uuid}?digest=sha256:...
+func (h *UploadHandler) Finalize(w http.ResponseWriter, r *http.Request) {
+	id := pathParam(r)
+
+	owner, err := ownerFromRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	digest := strings.TrimPrefix(r.URL.Query().Get("digest"), "sha256:")
+	if digest == "" {
+		http.Error(w, "missing digest query parameter", http.StatusBadRequest)
+		return
+	}
+
+	session, err := h.uploads.Finalize(r.Context(), id, owner, digest)
+	if err != nil {
+		writeUploadError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]string{"key": session.Key})
+}
+
+// Status reports the current offset of an in-progress upload.
+// GET /uploads/{uuid}
+func (h *UploadHandler) Status(w http.ResponseWriter, r *http.Request) {
+	id := pathParam(r)
+
+	owner, err := ownerFromRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	session, err := h.uploads.Get(r.Context(), id, owner)
+	if err != nil {
+		writeUploadError(w, err)
+		return
+	}
+
+	w.Header().Set("Range", fmt.Sprintf("0-%d", session.Offset))
+	w.Header().Set("Docker-Upload-UUID", session.ID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Abort cancels an in-progress upload and discards any bytes already sent.
+// DELETE /uploads/{uuid}
+func (h *UploadHandler) Abort(w http.ResponseWriter, r *http.Request) {
+	id := pathParam(r)
+
+	owner, err := ownerFromRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.uploads.Abort(r.Context(), id, owner); err != nil {
+		writeUploadError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeUploadError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrUploadSessionNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, service.ErrUploadOutOfOrder):
+		http.Error(w, err.Error(), http.StatusRequestedRangeNotSatisfiable)
+	case errors.Is(err, service.ErrUploadDigestMismatch):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, service.ErrUploadConflict):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// errUnauthenticated is returned by ownerFromRequest when auth middleware
+// hasn't populated a user ID on the request context.
+var errUnauthenticated = errors.New("request is not authenticated")
+
+// ownerFromRequest resolves the authenticated user for the request. Auth
+// middleware populates this in the request context for every
+// authenticated route; it returns errUnauthenticated rather than an empty
+// owner so callers can't accidentally scope a lookup to "" and match rows
+// that predate auth or were created without an owner.
+func ownerFromRequest(r *http.Request) (string, error) {
+	owner, ok := r.Context().Value(authUserIDContextKey).(string)
+	if !ok || owner == "" {
+		return "", errUnauthenticated
+	}
+	return owner, nil
+}
+
+// parseContentRangeStart parses the start offset out of a "start-end"
+// Content-Range header value. An empty header implies the client is
+// streaming from offset 0.
+func parseContentRangeStart(headerValue string) (int64, error) {
+	if headerValue == "" {
+		return 0, nil
+	}
+
+	parts := strings.SplitN(headerValue, "-", 2)
+	start, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid Content-Range header %q", headerValue)
+	}
+	return start, nil
+}
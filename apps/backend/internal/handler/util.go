@@ -0,0 +1,20 @@
+package handler
+
+import (
+	"net/http"
+	"path"
+)
+
+// pathParam extracts the last path segment, e.g. "abc" from
+// "/uploads/abc" or "/keys/abc". Handlers that need more than one
+// path parameter should switch to the router's own param extraction
+// instead of relying on this.
+func pathParam(r *http.Request) string {
+	return path.Base(r.URL.Path)
+}
+
+// secondToLastPathParam extracts the {id} path segment from routes shaped
+// like "/files/{id}/complete".
+func secondToLastPathParam(r *http.Request) string {
+	return path.Base(path.Dir(r.URL.Path))
+}
@@ -0,0 +1,241 @@
+package service
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MinaSamirSaad/go-tasker/internal/config"
+	"github.com/MinaSamirSaad/go-tasker/internal/errordetail"
+	"github.com/MinaSamirSaad/go-tasker/internal/lib/job"
+	"github.com/MinaSamirSaad/go-tasker/internal/server"
+	"github.com/MinaSamirSaad/go-tasker/internal/storage"
+)
+
+// backupSubsystem is the errordetail subsystem name backup failures are
+// grouped under.
+const backupSubsystem = "backup"
+
+// AutoBackupService periodically dumps the Postgres database and uploads
+// the archive to the configured storage bucket, pruning old backups
+// according to the configured retention policy.
+type AutoBackupService struct {
+	cfg       *config.Config
+	storage   storage.Backend
+	errDetail *errordetail.Service
+}
+
+func NewAutoBackupService(s *server.Server, store storage.Backend, errDetail *errordetail.Service) *AutoBackupService {
+	return &AutoBackupService{
+		cfg:       s.Config,
+		storage:   store,
+		errDetail: errDetail,
+	}
+}
+
+// Register schedules the backup job with the cron job service if backups
+// are enabled in config. It is a no-op otherwise.
+func (b *AutoBackupService) Register(jobService *job.JobService) error {
+	backupCfg := b.cfg.Cron.Backup
+	if !backupCfg.Enabled {
+		return nil
+	}
+
+	return jobService.RegisterJob("auto_backup", backupCfg.CronExpr, func(ctx context.Context) error {
+		_, err := b.Run(ctx)
+		return err
+	})
+}
+
+// Run dumps the database, uploads the archive and enforces retention,
+// returning the object key of the uploaded backup.
+func (b *AutoBackupService) Run(ctx context.Context) (string, error) {
+	backupCfg := b.cfg.Cron.Backup
+	key := renderKeyPrefix(backupCfg.KeyPrefixTemplate, time.Now().UTC())
+
+	reader, writer := io.Pipe()
+
+	dumpErrCh := make(chan error, 1)
+	go func() {
+		err := b.streamDump(ctx, writer, backupCfg.Compress)
+		// CloseWithError (rather than a plain Close) makes the reader
+		// side see the failure instead of a clean EOF, so a partial
+		// dump doesn't get uploaded as if it were a complete backup.
+		writer.CloseWithError(err)
+		dumpErrCh <- err
+	}()
+
+	uploadErr := b.storage.UploadFile(ctx, key, reader)
+	if uploadErr != nil {
+		// UploadFile may have given up without draining reader to EOF
+		// (e.g. a transient mid-stream failure). Close it so the writer
+		// side — and the pg_dump process blocked writing into it via
+		// streamDump — unblocks instead of leaking a goroutine and a
+		// stuck subprocess on every upload hiccup.
+		reader.CloseWithError(uploadErr)
+	}
+	dumpErr := <-dumpErrCh
+
+	if uploadErr != nil || dumpErr != nil {
+		// The dump failed partway through, or the reader bailed out
+		// because of it; either way whatever was written under key is
+		// corrupt and must not be left in the bucket as if it were a
+		// valid backup.
+		runErr := errOrDump(uploadErr, dumpErr)
+		if delErr := b.storage.DeleteFile(ctx, key); delErr != nil {
+			runErr = fmt.Errorf("%w (cleanup of corrupt backup also failed: %v)", runErr, delErr)
+		}
+		b.recordFailure(ctx, "dump_failed", runErr)
+		return "", fmt.Errorf("dump database: %w", runErr)
+	}
+
+	if err := b.enforceRetention(ctx, backupCfg); err != nil {
+		b.recordFailure(ctx, "retention_failed", err)
+		return key, fmt.Errorf("enforce retention: %w", err)
+	}
+
+	return key, nil
+}
+
+// recordFailure feeds a failed backup run into the observability
+// subsystem, whether Run was triggered by the cron job or an on-demand
+// POST /admin/backups/run call, so operators see it in GET /admin/errors
+// instead of only noticing a gap once backups stop showing up in
+// GET /admin/backups. Recording is best-effort: a failure to classify a
+// failure shouldn't mask the original error returned to the caller.
+func (b *AutoBackupService) recordFailure(ctx context.Context, code string, err error) {
+	_, _, _ = b.errDetail.Record(ctx, backupSubsystem, code, "backup_run", err, "", "")
+}
+
+// errOrDump prefers dumpErr over uploadErr when both are set, since the
+// dump failure is almost always the root cause of the upload also
+// failing (the reader saw CloseWithError).
+func errOrDump(uploadErr, dumpErr error) error {
+	if dumpErr != nil {
+		return dumpErr
+	}
+	return uploadErr
+}
+
+// streamDump runs pg_dump against the configured database, optionally
+// gzip-compressing the output, and writes it to dst as it's produced so
+// the caller can stream the dump straight to storage without touching
+// local disk.
+func (b *AutoBackupService) streamDump(ctx context.Context, dst io.Writer, compress bool) error {
+	db := b.cfg.Database
+
+	cmd := exec.CommandContext(ctx, "pg_dump",
+		"--host", db.Host,
+		"--port", strconv.Itoa(db.Port),
+		"--username", db.User,
+		"--dbname", db.Name,
+		"--no-password",
+		"--format", "plain",
+	)
+	// cmd.Env is nil here, and exec.Cmd treats a non-nil Env as a full
+	// replacement of the subprocess's environment rather than an
+	// extension of it — start from os.Environ() so pg_dump still gets
+	// PATH, locale, etc.
+	cmd.Env = append(os.Environ(), "PGPASSWORD="+db.Password)
+
+	var out io.Writer = dst
+	var gz *gzip.Writer
+	if compress {
+		gz = gzip.NewWriter(dst)
+		out = gz
+	}
+	cmd.Stdout = out
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("pg_dump: %w: %s", err, stderr.String())
+	}
+
+	if gz != nil {
+		return gz.Close()
+	}
+	return nil
+}
+
+// List returns the backups currently stored under the configured key
+// prefix, most recent first.
+func (b *AutoBackupService) List(ctx context.Context) ([]storage.ObjectInfo, error) {
+	prefix := backupListPrefix(b.cfg.Cron.Backup.KeyPrefixTemplate)
+	objects, err := b.storage.ListPrefix(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, j := 0, len(objects)-1; i < j; i, j = i+1, j-1 {
+		objects[i], objects[j] = objects[j], objects[i]
+	}
+
+	return objects, nil
+}
+
+// enforceRetention deletes backups under the configured prefix beyond the
+// retention count and/or older than the retention window.
+func (b *AutoBackupService) enforceRetention(ctx context.Context, cfg config.BackupConfig) error {
+	if cfg.RetentionCount <= 0 && cfg.RetentionDays <= 0 {
+		return nil
+	}
+
+	prefix := backupListPrefix(cfg.KeyPrefixTemplate)
+	objects, err := b.storage.ListPrefix(ctx, prefix)
+	if err != nil {
+		return err
+	}
+
+	cutoff := time.Now().UTC().AddDate(0, 0, -cfg.RetentionDays)
+
+	toDelete := map[string]struct{}{}
+	if cfg.RetentionDays > 0 {
+		for _, obj := range objects {
+			if obj.LastModified.Before(cutoff) {
+				toDelete[obj.Key] = struct{}{}
+			}
+		}
+	}
+
+	if cfg.RetentionCount > 0 && len(objects) > cfg.RetentionCount {
+		for _, obj := range objects[:len(objects)-cfg.RetentionCount] {
+			toDelete[obj.Key] = struct{}{}
+		}
+	}
+
+	for key := range toDelete {
+		if err := b.storage.DeleteFile(ctx, key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// renderKeyPrefix substitutes {timestamp} and {date} placeholders in the
+// configured template with the given time.
+func renderKeyPrefix(template string, t time.Time) string {
+	replacer := strings.NewReplacer(
+		"{timestamp}", strconv.FormatInt(t.Unix(), 10),
+		"{date}", t.Format("2006-01-02"),
+	)
+	return replacer.Replace(template)
+}
+
+// backupListPrefix returns the portion of the key template preceding the
+// first placeholder, used to scope ListPrefix to this job's backups.
+func backupListPrefix(template string) string {
+	if idx := strings.IndexByte(template, '{'); idx >= 0 {
+		return template[:idx]
+	}
+	return template
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/MinaSamirSaad/go-tasker/internal/accesskey"
+	"github.com/MinaSamirSaad/go-tasker/internal/config"
+	"github.com/MinaSamirSaad/go-tasker/internal/server"
+	"github.com/MinaSamirSaad/go-tasker/internal/storage"
+)
+
+var (
+	ErrPendingUploadNotFound = errors.New("pending upload not found")
+	ErrPendingUploadExpired  = errors.New("pending upload has expired")
+	ErrUploadSizeMismatch    = errors.New("uploaded object size does not match the expected size")
+)
+
+// PendingUpload tracks a file a client was given a presigned PUT URL for,
+// until it's verified and committed.
+type PendingUpload struct {
+	ID           string
+	Key          string
+	Owner        string
+	ContentType  string
+	ExpectedSize int64
+	ExpiresAt    time.Time
+	Committed    bool
+	CreatedAt    time.Time
+}
+
+// PresignService issues presigned upload/download URLs for direct
+// browser-to-storage transfers, tracking pending uploads in Postgres so
+// a later presign-download can't be issued for a file whose upload was
+// never verified.
+type PresignService struct {
+	db      *sql.DB
+	storage *storage.S3Storage
+	cfg     *config.Config
+}
+
+func NewPresignService(s *server.Server, store *storage.S3Storage) *PresignService {
+	return &PresignService{
+		db:      s.DB,
+		storage: store,
+		cfg:     s.Config,
+	}
+}
+
+func (p *PresignService) ttl() time.Duration {
+	seconds := p.cfg.AWS.PresignTTLSeconds
+	if seconds <= 0 || seconds > config.MaxPresignTTLSeconds {
+		seconds = config.MaxPresignTTLSeconds
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// CreateUpload issues a presigned PUT URL for a new file owned by owner
+// and records a pending-upload row so Complete can later verify it.
+func (p *PresignService) CreateUpload(ctx context.Context, owner, contentType string, expectedSize int64) (*PendingUpload, string, error) {
+	id := uuid.NewString()
+	key := accesskey.UserPrefix(owner) + "files/" + id
+	ttl := p.ttl()
+	expiresAt := time.Now().Add(ttl)
+
+	uploadURL, err := p.storage.PresignPutURL(ctx, key, contentType, ttl)
+	if err != nil {
+		return nil, "", fmt.Errorf("presign upload url: %w", err)
+	}
+
+	_, err = p.db.ExecContext(ctx, `
+		INSERT INTO pending_uploads (id, object_key, owner, content_type, expected_size, expires_at, committed, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, false, now())
+	`, id, key, owner, contentType, expectedSize, expiresAt)
+	if err != nil {
+		return nil, "", fmt.Errorf("insert pending upload: %w", err)
+	}
+
+	return &PendingUpload{
+		ID:           id,
+		Key:          key,
+		Owner:        owner,
+		ContentType:  contentType,
+		ExpectedSize: expectedSize,
+		ExpiresAt:    expiresAt,
+	}, uploadURL, nil
+}
+
+// Complete verifies, via HeadObject, that the object referenced by id was
+// actually uploaded and matches the size the caller declared up front,
+// then flips the pending-upload row to committed.
+func (p *PresignService) Complete(ctx context.Context, id, owner string) (*PendingUpload, error) {
+	pending, err := p.get(ctx, id, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := p.storage.StatFile(ctx, pending.Key)
+	if err != nil {
+		return nil, fmt.Errorf("stat uploaded object: %w", err)
+	}
+	if info.Size != pending.ExpectedSize {
+		return nil, ErrUploadSizeMismatch
+	}
+
+	_, err = p.db.ExecContext(ctx, `UPDATE pending_uploads SET committed = true WHERE id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	pending.Committed = true
+
+	return pending, nil
+}
+
+// PresignDownload issues a presigned GET URL for a previously committed
+// upload.
+func (p *PresignService) PresignDownload(ctx context.Context, id, owner string) (string, error) {
+	pending, err := p.get(ctx, id, owner)
+	if err != nil {
+		return "", err
+	}
+	if !pending.Committed {
+		return "", fmt.Errorf("upload %s has not been completed", id)
+	}
+
+	return p.storage.PresignGetURL(ctx, pending.Key, p.ttl())
+}
+
+// get loads the pending upload referenced by id, scoped to owner. It
+// returns ErrPendingUploadNotFound both when the row doesn't exist and
+// when it belongs to a different owner, so callers can't use the
+// response to discover pending-upload IDs they don't own.
+func (p *PresignService) get(ctx context.Context, id, owner string) (*PendingUpload, error) {
+	var pending PendingUpload
+	err := p.db.QueryRowContext(ctx, `
+		SELECT id, object_key, owner, content_type, expected_size, expires_at, committed, created_at
+		FROM pending_uploads
+		WHERE id = $1 AND owner = $2
+	`, id, owner).Scan(&pending.ID, &pending.Key, &pending.Owner, &pending.ContentType, &pending.ExpectedSize, &pending.ExpiresAt, &pending.Committed, &pending.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrPendingUploadNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !pending.Committed && time.Now().After(pending.ExpiresAt) {
+		return nil, ErrPendingUploadExpired
+	}
+
+	return &pending, nil
+}
@@ -1,21 +1,69 @@
 package service
 
 import (
+	"context"
+	"fmt"
+
+	"github.com/MinaSamirSaad/go-tasker/internal/errordetail"
 	"github.com/MinaSamirSaad/go-tasker/internal/lib/job"
 	"github.com/MinaSamirSaad/go-tasker/internal/repository"
 	"github.com/MinaSamirSaad/go-tasker/internal/server"
+	"github.com/MinaSamirSaad/go-tasker/internal/storage"
 )
 
 type Services struct {
-	Auth *AuthService
-	Job  *job.JobService
+	Auth       *AuthService
+	Job        *job.JobService
+	AutoBackup *AutoBackupService
+	Upload     *UploadService
+	Presign    *PresignService
+	Storage    storage.Backend
 }
 
 func NewServices(s *server.Server, repos *repository.Repositories) (*Services, error) {
 	authService := NewAuthService(s)
 
+	storageURL := s.Config.AWS.StorageURL
+	if storageURL == "" {
+		storageURL = "s3://" + s.Config.AWS.UploadBucket
+	}
+
+	storageBackend, err := storage.Open(context.Background(), storageURL, &s.Config.AWS)
+	if err != nil {
+		return nil, fmt.Errorf("open storage backend: %w", err)
+	}
+
+	// Classify every job failure so operators see it aggregated in
+	// GET /admin/errors instead of having to grep job logs.
+	errorDetailService := errordetail.NewService(s)
+	s.Job.OnFailure(func(ctx context.Context, subsystem, code string, jobErr error) (retryable bool) {
+		_, retryable, _ = errorDetailService.Record(ctx, subsystem, code, "job_failure", jobErr, "", "")
+		return retryable
+	})
+
+	autoBackupService := NewAutoBackupService(s, storageBackend, errorDetailService)
+	if err := autoBackupService.Register(s.Job); err != nil {
+		return nil, err
+	}
+
+	// Resumable uploads drive the S3 multipart API directly, so they use a
+	// concrete S3Storage rather than the generic storage.Backend.
+	uploadStorage, err := storage.NewS3Storage(&s.Config.AWS)
+	if err != nil {
+		return nil, fmt.Errorf("open upload storage: %w", err)
+	}
+	uploadService := NewUploadService(s, uploadStorage)
+
+	// Presigning also drives the S3 API directly (PresignPutURL/PresignGetURL),
+	// so it shares the same concrete S3Storage as resumable uploads.
+	presignService := NewPresignService(s, uploadStorage)
+
 	return &Services{
-		Job:  s.Job,
-		Auth: authService,
+		Job:        s.Job,
+		Auth:       authService,
+		AutoBackup: autoBackupService,
+		Upload:     uploadService,
+		Presign:    presignService,
+		Storage:    storageBackend,
 	}, nil
 }
@@ -0,0 +1,334 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/MinaSamirSaad/go-tasker/internal/accesskey"
+	"github.com/MinaSamirSaad/go-tasker/internal/server"
+	"github.com/MinaSamirSaad/go-tasker/internal/storage"
+)
+
+// uploadSessionTTL bounds how long an abandoned resumable upload survives
+// before Redis expires it and the underlying multipart upload leaks; the
+// S3 bucket lifecycle policy is expected to garbage-collect those.
+const uploadSessionTTL = 24 * time.Hour
+
+var (
+	ErrUploadSessionNotFound = fmt.Errorf("upload session not found")
+	ErrUploadOutOfOrder      = fmt.Errorf("chunk does not start at the current offset")
+	ErrUploadDigestMismatch  = fmt.Errorf("uploaded content does not match the expected digest")
+	ErrUploadConflict        = fmt.Errorf("upload session was modified concurrently, retry the request")
+)
+
+// maxAppendChunkAttempts bounds how many times AppendChunk retries its
+// optimistic-locked read-modify-write against a session before giving up
+// and surfacing ErrUploadConflict to the caller.
+const maxAppendChunkAttempts = 3
+
+type uploadPart struct {
+	Number int32  `json:"number"`
+	ETag   string `json:"etag"`
+}
+
+// UploadSession tracks one in-progress resumable upload.
+type UploadSession struct {
+	ID           string       `json:"id"`
+	Key          string       `json:"key"`
+	UploadID     string       `json:"upload_id"`
+	Owner        string       `json:"owner"`
+	ExpectedSize int64        `json:"expected_size"`
+	Offset       int64        `json:"offset"`
+	Parts        []uploadPart `json:"parts"`
+	NextPart     int32        `json:"next_part"`
+	Pending      []byte       `json:"pending"`
+	DigestState  []byte       `json:"digest_state"`
+	CreatedAt    time.Time    `json:"created_at"`
+}
+
+// UploadService implements resumable chunked uploads on top of an S3
+// multipart upload, mirroring the Docker Registry blob-upload protocol.
+// Session state lives in Redis so an upload can resume across server
+// restarts.
+type UploadService struct {
+	redis   *redis.Client
+	storage storage.MultipartBackend
+}
+
+func NewUploadService(s *server.Server, store storage.MultipartBackend) *UploadService {
+	return &UploadService{
+		redis:   s.Redis,
+		storage: store,
+	}
+}
+
+func sessionRedisKey(id string) string {
+	return "upload:session:" + id
+}
+
+// CreateSession starts a new resumable upload for owner and returns its
+// session, with Offset always 0.
+func (u *UploadService) CreateSession(ctx context.Context, owner string, expectedSize int64) (*UploadSession, error) {
+	id := uuid.NewString()
+	key := accesskey.UserPrefix(owner) + "uploads/" + id
+
+	uploadID, err := u.storage.CreateMultipartUpload(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("create multipart upload: %w", err)
+	}
+
+	digestState, err := marshalHash(sha256.New())
+	if err != nil {
+		return nil, err
+	}
+
+	session := &UploadSession{
+		ID:           id,
+		Key:          key,
+		UploadID:     uploadID,
+		Owner:        owner,
+		ExpectedSize: expectedSize,
+		NextPart:     1,
+		DigestState:  digestState,
+		CreatedAt:    time.Now().UTC(),
+	}
+
+	if err := u.save(ctx, session); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// get loads a session by ID without checking ownership; callers that
+// expose a session to a caller (rather than just comparing against it)
+// must go through Get instead.
+func (u *UploadService) get(ctx context.Context, id string) (*UploadSession, error) {
+	raw, err := u.redis.Get(ctx, sessionRedisKey(id)).Bytes()
+	if err == redis.Nil {
+		return nil, ErrUploadSessionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return decodeSession(raw)
+}
+
+func decodeSession(raw []byte) (*UploadSession, error) {
+	var session UploadSession
+	if err := json.Unmarshal(raw, &session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// Get loads a session by ID, scoped to owner. It returns
+// ErrUploadSessionNotFound both when the session doesn't exist and when
+// it belongs to a different owner, so callers can't use the response to
+// discover session IDs they don't own.
+func (u *UploadService) Get(ctx context.Context, id, owner string) (*UploadSession, error) {
+	session, err := u.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if session.Owner != owner {
+		return nil, ErrUploadSessionNotFound
+	}
+	return session, nil
+}
+
+// AppendChunk appends body to the session starting at rangeStart, which
+// must equal the session's current offset. Small chunks are buffered in
+// Redis until they reach S3's 5 MiB minimum part size, at which point
+// they're flushed as a new multipart part.
+//
+// The read-modify-write of the session is done under a Redis WATCH on
+// its key so two concurrent PATCHes for the same session can't both pass
+// the stale-offset check against the same snapshot: whichever commits
+// second has its transaction aborted and retries against the now-current
+// offset, instead of silently clobbering the winner's bookkeeping.
+func (u *UploadService) AppendChunk(ctx context.Context, id, owner string, rangeStart int64, body []byte) (*UploadSession, error) {
+	key := sessionRedisKey(id)
+
+	for attempt := 0; attempt < maxAppendChunkAttempts; attempt++ {
+		var result *UploadSession
+
+		txErr := u.redis.Watch(ctx, func(tx *redis.Tx) error {
+			raw, err := tx.Get(ctx, key).Bytes()
+			if err == redis.Nil {
+				return ErrUploadSessionNotFound
+			}
+			if err != nil {
+				return err
+			}
+			session, err := decodeSession(raw)
+			if err != nil {
+				return err
+			}
+
+			if session.Owner != owner {
+				return ErrUploadSessionNotFound
+			}
+			if rangeStart != session.Offset {
+				return ErrUploadOutOfOrder
+			}
+
+			digest, err := unmarshalHash(session.DigestState)
+			if err != nil {
+				return err
+			}
+			digest.Write(body)
+			session.DigestState, err = marshalHash(digest)
+			if err != nil {
+				return err
+			}
+
+			session.Pending = append(session.Pending, body...)
+			session.Offset += int64(len(body))
+
+			if err := u.flushPending(ctx, session, storage.MinMultipartPartSize); err != nil {
+				return err
+			}
+
+			newRaw, err := json.Marshal(session)
+			if err != nil {
+				return err
+			}
+
+			_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				pipe.Set(ctx, key, newRaw, uploadSessionTTL)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+
+			result = session
+			return nil
+		}, key)
+
+		if txErr == nil {
+			return result, nil
+		}
+		if txErr == redis.TxFailedErr {
+			// Another PATCH committed first and changed the watched
+			// key; retry against the now-current offset.
+			continue
+		}
+		return nil, txErr
+	}
+
+	return nil, ErrUploadConflict
+}
+
+// flushPending uploads session.Pending as a new part whenever it reaches
+// threshold bytes, clearing it afterwards. Pass threshold=1 to force a
+// final flush of whatever remains, regardless of size.
+func (u *UploadService) flushPending(ctx context.Context, session *UploadSession, threshold int) error {
+	if len(session.Pending) == 0 || len(session.Pending) < threshold {
+		return nil
+	}
+
+	etag, err := u.storage.UploadPart(ctx, session.Key, session.UploadID, session.NextPart, session.Pending)
+	if err != nil {
+		return fmt.Errorf("upload part %d: %w", session.NextPart, err)
+	}
+
+	session.Parts = append(session.Parts, uploadPart{Number: session.NextPart, ETag: etag})
+	session.NextPart++
+	session.Pending = nil
+	return nil
+}
+
+// Finalize checks the session's running digest against expectedDigest (a
+// lowercase hex sha256 digest, no "sha256:" prefix), then flushes any
+// remaining buffered bytes as the last part and completes the multipart
+// upload. On a digest mismatch nothing is flushed or completed.
+func (u *UploadService) Finalize(ctx context.Context, id, owner, expectedDigest string) (*UploadSession, error) {
+	session, err := u.Get(ctx, id, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	digest, err := unmarshalHash(session.DigestState)
+	if err != nil {
+		return nil, err
+	}
+	actualDigest := hex.EncodeToString(digest.Sum(nil))
+	if actualDigest != expectedDigest {
+		return nil, ErrUploadDigestMismatch
+	}
+
+	if err := u.flushPending(ctx, session, 1); err != nil {
+		return nil, err
+	}
+
+	parts := make([]storage.CompletedPart, len(session.Parts))
+	for i, p := range session.Parts {
+		parts[i] = storage.CompletedPart{PartNumber: p.Number, ETag: p.ETag}
+	}
+
+	if err := u.storage.CompleteMultipartUpload(ctx, session.Key, session.UploadID, parts); err != nil {
+		return nil, fmt.Errorf("complete multipart upload: %w", err)
+	}
+
+	if err := u.redis.Del(ctx, sessionRedisKey(id)).Err(); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// Abort discards the session and the underlying multipart upload.
+func (u *UploadService) Abort(ctx context.Context, id, owner string) error {
+	session, err := u.Get(ctx, id, owner)
+	if err != nil {
+		return err
+	}
+
+	if err := u.storage.AbortMultipartUpload(ctx, session.Key, session.UploadID); err != nil {
+		return err
+	}
+
+	return u.redis.Del(ctx, sessionRedisKey(id)).Err()
+}
+
+func (u *UploadService) save(ctx context.Context, session *UploadSession) error {
+	raw, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+	return u.redis.Set(ctx, sessionRedisKey(session.ID), raw, uploadSessionTTL).Err()
+}
+
+// marshalHash serializes a sha256 hash's running state so it can be
+// resumed later; crypto/sha256's Hash implements encoding.BinaryMarshaler
+// for exactly this purpose.
+func marshalHash(h hash.Hash) ([]byte, error) {
+	marshaler, ok := h.(encoding.BinaryMarshaler)
+	if !ok {
+		return nil, fmt.Errorf("hash does not support binary marshaling")
+	}
+	return marshaler.MarshalBinary()
+}
+
+func unmarshalHash(state []byte) (hash.Hash, error) {
+	h := sha256.New()
+	unmarshaler, ok := h.(encoding.BinaryUnmarshaler)
+	if !ok {
+		return nil, fmt.Errorf("hash does not support binary unmarshaling")
+	}
+	if err := unmarshaler.UnmarshalBinary(state); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
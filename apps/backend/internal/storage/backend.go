@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+
+	appconfig "github.com/MinaSamirSaad/go-tasker/internal/config"
+)
+
+// Backend is the storage abstraction every driver (S3, local filesystem,
+// in-memory for tests, ...) implements. Callers should depend on this
+// interface rather than on a concrete driver so the backend can be swapped
+// via config alone.
+type Backend interface {
+	UploadFile(ctx context.Context, key string, body io.Reader) error
+	DownloadFile(ctx context.Context, key string) (io.ReadCloser, error)
+	DeleteFile(ctx context.Context, key string) error
+	StatFile(ctx context.Context, key string) (ObjectInfo, error)
+	ListPrefix(ctx context.Context, prefix string) ([]ObjectInfo, error)
+	// IterateStream calls fn once per object under prefix, oldest first,
+	// stopping at the first error returned by fn.
+	IterateStream(ctx context.Context, prefix string, fn func(ObjectInfo, io.Reader) error) error
+	PresignGetURL(ctx context.Context, key string, ttl time.Duration) (string, error)
+	PresignPutURL(ctx context.Context, key, contentType string, ttl time.Duration) (string, error)
+}
+
+// OpenFunc constructs a Backend from a parsed storage URL and the app's AWS
+// config (used by drivers, such as s3, that need credentials/region/etc).
+type OpenFunc func(ctx context.Context, u *url.URL, cfg *appconfig.AWSConfig) (Backend, error)
+
+var registry = map[string]OpenFunc{}
+
+// Register adds a driver under the given URL scheme (e.g. "s3", "file").
+// Drivers register themselves from an init() function.
+func Register(scheme string, open OpenFunc) {
+	registry[scheme] = open
+}
+
+// Open resolves a storage backend from a well-known-filesystem-style URL,
+// e.g. "s3://my-bucket", "file:///var/data/uploads", or "memory://" in
+// tests. The scheme selects the driver; cfg supplies AWS credentials for
+// drivers that need them.
+func Open(ctx context.Context, rawURL string, cfg *appconfig.AWSConfig) (Backend, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse storage url %q: %w", rawURL, err)
+	}
+
+	open, ok := registry[u.Scheme]
+	if !ok {
+		return nil, fmt.Errorf("no storage backend registered for scheme %q", u.Scheme)
+	}
+
+	return open(ctx, u, cfg)
+}
+
+// ObjectInfo is a minimal summary of an object returned by ListPrefix,
+// StatFile and IterateStream.
+type ObjectInfo struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+}
@@ -0,0 +1,149 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	appconfig "github.com/MinaSamirSaad/go-tasker/internal/config"
+)
+
+func init() {
+	Register("file", openLocalBackend)
+}
+
+// LocalBackend stores objects as files under a root directory on the local
+// filesystem. It's primarily useful for local development and tests where
+// standing up S3/MinIO isn't worth the overhead.
+type LocalBackend struct {
+	root string
+}
+
+func openLocalBackend(ctx context.Context, u *url.URL, cfg *appconfig.AWSConfig) (Backend, error) {
+	root := u.Path
+	if root == "" {
+		root = u.Opaque
+	}
+	if root == "" {
+		return nil, fmt.Errorf("file:// storage url must specify a path, got %q", u.String())
+	}
+
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("create local storage root: %w", err)
+	}
+
+	return &LocalBackend{root: root}, nil
+}
+
+func (l *LocalBackend) path(key string) string {
+	return filepath.Join(l.root, filepath.FromSlash(key))
+}
+
+func (l *LocalBackend) UploadFile(ctx context.Context, key string, body io.Reader) error {
+	dst := l.path(key)
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+
+	f, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(f, body)
+	return err
+}
+
+func (l *LocalBackend) DownloadFile(ctx context.Context, key string) (io.ReadCloser, error) {
+	return os.Open(l.path(key))
+}
+
+func (l *LocalBackend) DeleteFile(ctx context.Context, key string) error {
+	return os.Remove(l.path(key))
+}
+
+func (l *LocalBackend) StatFile(ctx context.Context, key string) (ObjectInfo, error) {
+	fi, err := os.Stat(l.path(key))
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	return ObjectInfo{Key: key, Size: fi.Size(), LastModified: fi.ModTime()}, nil
+}
+
+func (l *LocalBackend) ListPrefix(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+
+	err := filepath.WalkDir(l.root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(l.root, path)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		objects = append(objects, ObjectInfo{Key: key, Size: fi.Size(), LastModified: fi.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(objects, func(i, j int) bool {
+		return objects[i].LastModified.Before(objects[j].LastModified)
+	})
+
+	return objects, nil
+}
+
+func (l *LocalBackend) IterateStream(ctx context.Context, prefix string, fn func(ObjectInfo, io.Reader) error) error {
+	objects, err := l.ListPrefix(ctx, prefix)
+	if err != nil {
+		return err
+	}
+
+	for _, obj := range objects {
+		f, err := l.DownloadFile(ctx, obj.Key)
+		if err != nil {
+			return err
+		}
+		err = fn(obj, f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PresignGetURL and PresignPutURL have no local-filesystem equivalent of a
+// browser-facing URL, so they return file:// URLs pointing directly at the
+// object; these are only meaningful to callers running on the same host
+// (e.g. local dev, tests) and are not valid outside that context.
+func (l *LocalBackend) PresignGetURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	return "file://" + l.path(key), nil
+}
+
+func (l *LocalBackend) PresignPutURL(ctx context.Context, key, contentType string, ttl time.Duration) (string, error) {
+	return "file://" + l.path(key), nil
+}
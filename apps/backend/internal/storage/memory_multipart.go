@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+)
+
+// memoryMultipartUpload tracks the parts uploaded so far for one
+// in-progress MemoryBackend multipart upload.
+type memoryMultipartUpload struct {
+	key   string
+	parts map[int32][]byte
+}
+
+// CreateMultipartUpload starts a new in-memory multipart upload for key
+// and returns a synthetic upload ID.
+func (m *MemoryBackend) CreateMultipartUpload(ctx context.Context, key string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.nextUploadID++
+	uploadID := fmt.Sprintf("mem-upload-%d", m.nextUploadID)
+	m.uploads[uploadID] = &memoryMultipartUpload{key: key, parts: map[int32][]byte{}}
+	return uploadID, nil
+}
+
+// UploadPart buffers a single part of an in-progress multipart upload and
+// returns a synthetic ETag, required to complete the upload.
+func (m *MemoryBackend) UploadPart(ctx context.Context, key, uploadID string, partNumber int32, body []byte) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	upload, ok := m.uploads[uploadID]
+	if !ok {
+		return "", fmt.Errorf("memory storage: multipart upload %q not found", uploadID)
+	}
+
+	data := append([]byte(nil), body...)
+	upload.parts[partNumber] = data
+	return fmt.Sprintf("mem-etag-%d-%d", partNumber, len(data)), nil
+}
+
+// CompleteMultipartUpload concatenates every part in order and stores the
+// result under key, mirroring S3Storage's behavior of assembling the
+// parts server-side.
+func (m *MemoryBackend) CompleteMultipartUpload(ctx context.Context, key, uploadID string, parts []CompletedPart) error {
+	m.mu.Lock()
+	upload, ok := m.uploads[uploadID]
+	if !ok {
+		m.mu.Unlock()
+		return fmt.Errorf("memory storage: multipart upload %q not found", uploadID)
+	}
+
+	var body bytes.Buffer
+	for _, part := range parts {
+		data, ok := upload.parts[part.PartNumber]
+		if !ok {
+			m.mu.Unlock()
+			return fmt.Errorf("memory storage: part %d was never uploaded", part.PartNumber)
+		}
+		body.Write(data)
+	}
+	delete(m.uploads, uploadID)
+	m.mu.Unlock()
+
+	return m.UploadFile(ctx, key, &body)
+}
+
+// AbortMultipartUpload discards an in-progress multipart upload and any
+// parts already buffered for it.
+func (m *MemoryBackend) AbortMultipartUpload(ctx context.Context, key, uploadID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.uploads, uploadID)
+	return nil
+}
@@ -0,0 +1,95 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// MinMultipartPartSize is S3's minimum part size for every part but the
+// last; callers of UploadPart must buffer smaller writes until they reach
+// this size (or the upload is being finalized).
+const MinMultipartPartSize = 5 * 1024 * 1024 // 5 MiB
+
+// CompletedPart identifies one uploaded part for CompleteMultipartUpload.
+type CompletedPart struct {
+	PartNumber int32
+	ETag       string
+}
+
+// MultipartBackend is implemented by storage drivers that support
+// multipart uploads. Callers that only need resumable-upload semantics
+// (not the full Backend interface) should depend on this instead of the
+// concrete S3Storage, so that logic can be unit tested against
+// MemoryBackend instead of real S3.
+type MultipartBackend interface {
+	CreateMultipartUpload(ctx context.Context, key string) (string, error)
+	UploadPart(ctx context.Context, key, uploadID string, partNumber int32, body []byte) (string, error)
+	CompleteMultipartUpload(ctx context.Context, key, uploadID string, parts []CompletedPart) error
+	AbortMultipartUpload(ctx context.Context, key, uploadID string) error
+}
+
+// CreateMultipartUpload starts a new S3 multipart upload for key and
+// returns its upload ID.
+func (s *S3Storage) CreateMultipartUpload(ctx context.Context, key string) (string, error) {
+	out, err := s.Client.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
+		Bucket: &s.Bucket,
+		Key:    &key,
+	})
+	if err != nil {
+		return "", err
+	}
+	return aws.ToString(out.UploadId), nil
+}
+
+// UploadPart uploads a single part of an in-progress multipart upload and
+// returns the ETag S3 assigned it, required to complete the upload.
+func (s *S3Storage) UploadPart(ctx context.Context, key, uploadID string, partNumber int32, body []byte) (string, error) {
+	out, err := s.Client.UploadPart(ctx, &s3.UploadPartInput{
+		Bucket:     &s.Bucket,
+		Key:        &key,
+		UploadId:   &uploadID,
+		PartNumber: &partNumber,
+		Body:       bytes.NewReader(body),
+	})
+	if err != nil {
+		return "", err
+	}
+	return aws.ToString(out.ETag), nil
+}
+
+// CompleteMultipartUpload finalizes the upload given the ETags of every
+// part previously uploaded with UploadPart, in order.
+func (s *S3Storage) CompleteMultipartUpload(ctx context.Context, key, uploadID string, parts []CompletedPart) error {
+	completedParts := make([]types.CompletedPart, len(parts))
+	for i, p := range parts {
+		completedParts[i] = types.CompletedPart{
+			PartNumber: aws.Int32(p.PartNumber),
+			ETag:       aws.String(p.ETag),
+		}
+	}
+
+	_, err := s.Client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:   &s.Bucket,
+		Key:      &key,
+		UploadId: &uploadID,
+		MultipartUpload: &types.CompletedMultipartUpload{
+			Parts: completedParts,
+		},
+	})
+	return err
+}
+
+// AbortMultipartUpload discards an in-progress multipart upload and any
+// parts already uploaded to it.
+func (s *S3Storage) AbortMultipartUpload(ctx context.Context, key, uploadID string) error {
+	_, err := s.Client.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   &s.Bucket,
+		Key:      &key,
+		UploadId: &uploadID,
+	})
+	return err
+}
@@ -2,23 +2,44 @@ package storage
 
 import (
 	"context"
+	"fmt"
 	"io"
+	"net/url"
+	"sort"
+	"time"
 
 	appconfig "github.com/MinaSamirSaad/go-tasker/internal/config"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 )
 
+func init() {
+	Register("s3", openS3Backend)
+}
+
+// S3Storage is the Backend implementation backed by S3 or any
+// S3-compatible endpoint (e.g. MinIO) reachable via AWSConfig.EndpointURL.
 type S3Storage struct {
 	Client *s3.Client
 	Bucket string
 }
 
-func NewS3Storage(cfg *appconfig.AWSConfig) (*S3Storage, error) {
-	awsCfg, err := config.LoadDefaultConfig(
-		context.TODO(),
+// openS3Backend is registered under the "s3" scheme. The bucket is taken
+// from the URL host (s3://my-bucket) if present, falling back to
+// cfg.UploadBucket so existing config keeps working unchanged.
+func openS3Backend(ctx context.Context, u *url.URL, cfg *appconfig.AWSConfig) (Backend, error) {
+	bucket := u.Host
+	if bucket == "" {
+		bucket = cfg.UploadBucket
+	}
+	return newS3Storage(ctx, cfg, bucket)
+}
+
+func newS3Storage(ctx context.Context, cfg *appconfig.AWSConfig, bucket string) (*S3Storage, error) {
+	configOptions := []func(*config.LoadOptions) error{
 		config.WithRegion(cfg.Region),
 		config.WithCredentialsProvider(
 			credentials.NewStaticCredentialsProvider(
@@ -27,22 +48,174 @@ func NewS3Storage(cfg *appconfig.AWSConfig) (*S3Storage, error) {
 				"",
 			),
 		),
-	)
+	}
+
+	awsCfg, err := config.LoadDefaultConfig(ctx, configOptions...)
 	if err != nil {
 		return nil, err
 	}
 
+	client := s3.NewFromConfig(awsCfg, func(o *s3.Options) {
+		if cfg.EndpointURL != "" {
+			o.BaseEndpoint = aws.String(cfg.EndpointURL)
+			// MinIO and most other S3-compatible endpoints expect
+			// path-style addressing (host/bucket/key) rather than
+			// AWS's virtual-hosted-style (bucket.host/key).
+			o.UsePathStyle = true
+		}
+	})
+
 	return &S3Storage{
-		Client: s3.NewFromConfig(awsCfg),
-		Bucket: cfg.UploadBucket,
+		Client: client,
+		Bucket: bucket,
 	}, nil
 }
 
-func (s *S3Storage) UploadFile(key string, body io.Reader) error {
-	_, err := s.Client.PutObject(context.TODO(), &s3.PutObjectInput{
+// NewS3Storage constructs an S3-backed driver directly against
+// cfg.UploadBucket. Prefer storage.Open so the backend can be swapped via
+// config alone; this remains for callers that specifically need S3.
+func NewS3Storage(cfg *appconfig.AWSConfig) (*S3Storage, error) {
+	return newS3Storage(context.Background(), cfg, cfg.UploadBucket)
+}
+
+func (s *S3Storage) UploadFile(ctx context.Context, key string, body io.Reader) error {
+	_, err := s.Client.PutObject(ctx, &s3.PutObjectInput{
 		Bucket: &s.Bucket,
 		Key:    &key,
 		Body:   body,
 	})
 	return err
 }
+
+func (s *S3Storage) DownloadFile(ctx context.Context, key string) (io.ReadCloser, error) {
+	out, err := s.Client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: &s.Bucket,
+		Key:    &key,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.Body, nil
+}
+
+// DeleteFile removes a single object from the bucket.
+func (s *S3Storage) DeleteFile(ctx context.Context, key string) error {
+	_, err := s.Client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: &s.Bucket,
+		Key:    &key,
+	})
+	return err
+}
+
+func (s *S3Storage) StatFile(ctx context.Context, key string) (ObjectInfo, error) {
+	out, err := s.Client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: &s.Bucket,
+		Key:    &key,
+	})
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+
+	var lastModified time.Time
+	if out.LastModified != nil {
+		lastModified = *out.LastModified
+	}
+
+	return ObjectInfo{
+		Key:          key,
+		Size:         aws.ToInt64(out.ContentLength),
+		LastModified: lastModified,
+	}, nil
+}
+
+// ListPrefix lists objects under prefix, oldest first, handling pagination.
+func (s *S3Storage) ListPrefix(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+
+	var continuationToken *string
+	for {
+		out, err := s.Client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{
+			Bucket:            &s.Bucket,
+			Prefix:            &prefix,
+			ContinuationToken: continuationToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, obj := range out.Contents {
+			var lastModified time.Time
+			if obj.LastModified != nil {
+				lastModified = *obj.LastModified
+			}
+			objects = append(objects, ObjectInfo{
+				Key:          aws.ToString(obj.Key),
+				Size:         aws.ToInt64(obj.Size),
+				LastModified: lastModified,
+			})
+		}
+
+		if out.IsTruncated == nil || !*out.IsTruncated {
+			break
+		}
+		continuationToken = out.NextContinuationToken
+	}
+
+	sort.Slice(objects, func(i, j int) bool {
+		return objects[i].LastModified.Before(objects[j].LastModified)
+	})
+
+	return objects, nil
+}
+
+// IterateStream calls fn once per object under prefix, oldest first,
+// downloading each object's body lazily and closing it after fn returns.
+func (s *S3Storage) IterateStream(ctx context.Context, prefix string, fn func(ObjectInfo, io.Reader) error) error {
+	objects, err := s.ListPrefix(ctx, prefix)
+	if err != nil {
+		return err
+	}
+
+	for _, obj := range objects {
+		body, err := s.DownloadFile(ctx, obj.Key)
+		if err != nil {
+			return fmt.Errorf("download %s: %w", obj.Key, err)
+		}
+
+		err = fn(obj, body)
+		body.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *S3Storage) PresignGetURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	presignClient := s3.NewPresignClient(s.Client)
+	req, err := presignClient.PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: &s.Bucket,
+		Key:    &key,
+	}, s3.WithPresignExpires(ttl))
+	if err != nil {
+		return "", err
+	}
+	return req.URL, nil
+}
+
+func (s *S3Storage) PresignPutURL(ctx context.Context, key, contentType string, ttl time.Duration) (string, error) {
+	presignClient := s3.NewPresignClient(s.Client)
+	input := &s3.PutObjectInput{
+		Bucket: &s.Bucket,
+		Key:    &key,
+	}
+	if contentType != "" {
+		input.ContentType = &contentType
+	}
+	req, err := presignClient.PresignPutObject(ctx, input, s3.WithPresignExpires(ttl))
+	if err != nil {
+		return "", err
+	}
+	return req.URL, nil
+}